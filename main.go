@@ -2,14 +2,24 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/csv"
 	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -75,13 +85,56 @@ func main() {
 			&cli.StringFlag{
 				Name:    "list",
 				Aliases: []string{"l"},
-				Usage:   "list of all contacts; which is exported from Excel with TAB delimiter and UTF-16 LE encoding with BOM",
+				Usage:   "list of all contacts; either a .xlsx workbook or a TAB delimited, UTF-16 LE with BOM export from Excel",
+			},
+			&cli.StringFlag{
+				Name:  "sheet",
+				Usage: "the sheet name to read the contact list from, when 'list' is a .xlsx workbook",
+				Value: defaultListSheetName,
 			},
 			&cli.StringFlag{
 				Name:    "excel",
 				Aliases: []string{"e"},
 				Usage:   "output excel files to include the class, name and QR code image; this option must be used with input 'list' file",
 			},
+			&cli.IntFlag{
+				Name:  "jobs",
+				Usage: "number of contacts to process concurrently; defaults to the number of CPUs",
+				Value: runtime.NumCPU(),
+			},
+			&cli.BoolFlag{
+				Name:  "stream",
+				Usage: fmt.Sprintf("write the output excel with excelize's StreamWriter instead of building it in memory; used automatically when the contact list has more than %d entries", streamAutoThreshold),
+			},
+			&cli.IntFlag{
+				Name:  "qr-size",
+				Usage: "the width and height, in pixels, of the rendered QR code PNG",
+				Value: defaultQRSize,
+			},
+			&cli.StringFlag{
+				Name:  "qr-ecc",
+				Usage: "the QR error-correction level: L, M, Q or H",
+				Value: "M",
+			},
+			&cli.StringFlag{
+				Name:  "qr-fg",
+				Usage: "the QR foreground color, as a #RRGGBB hex string",
+				Value: "#000000",
+			},
+			&cli.StringFlag{
+				Name:  "qr-bg",
+				Usage: "the QR background color, as a #RRGGBB hex string",
+				Value: "#FFFFFF",
+			},
+			&cli.StringFlag{
+				Name:  "qr-logo",
+				Usage: "a logo image to overlay at the center of every rendered QR code, at 1/5 the code size; forces --qr-ecc to H",
+			},
+			&cli.StringFlag{
+				Name:  "vcard-version",
+				Usage: fmt.Sprintf("the vCard version to emit: %s or %s", vcardVersion3, vcardVersion4),
+				Value: vcardVersion3,
+			},
 		},
 		Before: func(c *cli.Context) error {
 			if c.Bool("debug") {
@@ -101,7 +154,11 @@ func main() {
 }
 
 const (
-	defaultVCFExtension = ".vcf"
+	defaultVCFExtension  = ".vcf"
+	defaultListSheetName = "Sheet1"
+	defaultQRSize        = 256
+	vcardVersion3        = "3.0"
+	vcardVersion4        = "4.0"
 )
 
 type answerType int
@@ -120,14 +177,30 @@ type contact struct {
 	Cell     string
 	Email    string
 	Answer   answerType
+	Org      string
+	Title    string
+	Address  string
+	URL      string
+	Birthday string
+	Photo    string
 }
 
 func mainAction(c *cli.Context) error {
 	logger := log.Ctx(c.Context)
 
+	qrOpts, err := buildQROptions(c)
+	if err != nil {
+		return err
+	}
+
+	vcardVersion := c.String("vcard-version")
+	if vcardVersion != vcardVersion3 && vcardVersion != vcardVersion4 {
+		return fmt.Errorf("invalid vcard-version: %s", vcardVersion)
+	}
+
 	if c.NArg() > 0 {
 		for _, vcfFname := range c.Args().Slice() {
-			if err := geneateQRCodeByFile(vcfFname); err != nil {
+			if err := geneateQRCodeByFile(vcfFname, qrOpts); err != nil {
 				return err
 			}
 		}
@@ -142,40 +215,187 @@ func mainAction(c *cli.Context) error {
 	}
 
 	// followings are work with the input list file
-	contactLst, err := parseInputList(c.Context, dataFolder, lstFname)
+	contactLst, err := parseInputList(c.Context, dataFolder, lstFname, c.String("sheet"))
 	if err != nil {
 		return err
 	}
 	logger.Info().Msgf("read %d contacts", len(contactLst))
 
-	for idx, cnt := range contactLst {
-		logger.Debug().Interface("c", cnt).Msgf("%d", idx+1)
+	if err := generateContactAssets(c.Context, contactLst, c.Int("jobs"), qrOpts, vcardVersion); err != nil {
+		return err
+	}
 
-		if cnt.Answer == AnswerCustom {
-			if _, err := os.Stat(cnt.VcfFname); err != nil { // errors.Is(err, os.ErrNotExist)
-				logger.Error().Err(err).Interface("cnt", cnt).Msg("the record required customized vCard, which has error")
-				return err
-			}
-		} else if err := generateVCard(c.Context, cnt); err != nil { // vCard file is not exist
+	if outExcelFname != "" {
+		outExcelFname = filepath.Join(dataFolder, outExcelFname)
+		useStream := c.Bool("stream") || len(contactLst) > streamAutoThreshold
+		if err := generateExcelFile(c.Context, outExcelFname, contactLst, useStream); err != nil {
 			return err
 		}
+	}
 
-		if err := geneateQRCodeByFile(cnt.VcfFname); err != nil {
-			return err
+	return nil
+}
+
+// progressReportInterval is how often generateContactAssets logs the number
+// of contacts processed so far.
+const progressReportInterval = 20
+
+// generateContactAssets writes the vCard and QR code files for every contact
+// in contactLst, fanning the work out across a bounded pool of jobs workers.
+// contactLst itself is left untouched, so the later Excel assembly step sees
+// the contacts in the same order they were read. The first worker error
+// cancels the remaining work and is returned once every worker has stopped.
+func generateContactAssets(ctx context.Context, contactLst []*contact, jobs int, qrOpts *qrOptions, vcardVersion string) error {
+	logger := log.Ctx(ctx)
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexedContact struct {
+		idx int
+		cnt *contact
+	}
+	workCh := make(chan indexedContact)
+
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+	var completed int64
+
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for work := range workCh {
+				logger.Debug().Interface("c", work.cnt).Msgf("%d", work.idx+1)
+
+				if err := generateContactAsset(ctx, work.cnt, qrOpts, vcardVersion); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+
+				if n := atomic.AddInt64(&completed, 1); n%progressReportInterval == 0 {
+					logger.Info().Msgf("processed %d/%d contacts", n, len(contactLst))
+				}
+			}
+		}()
+	}
+
+feed:
+	for idx, cnt := range contactLst {
+		select {
+		case <-ctx.Done():
+			break feed
+		case workCh <- indexedContact{idx: idx, cnt: cnt}:
 		}
 	}
+	close(workCh)
+	wg.Wait()
 
-	if outExcelFname != "" {
-		outExcelFname = filepath.Join(dataFolder, outExcelFname)
-		if err := generateExcelFile(c.Context, outExcelFname, contactLst); err != nil {
+	return firstErr
+}
+
+// generateContactAsset writes a single contact's vCard (unless it already
+// carries a hand-customized one) and renders its QR code.
+func generateContactAsset(ctx context.Context, cnt *contact, qrOpts *qrOptions, vcardVersion string) error {
+	logger := log.Ctx(ctx)
+
+	if cnt.Answer == AnswerCustom {
+		if _, err := os.Stat(cnt.VcfFname); err != nil { // errors.Is(err, os.ErrNotExist)
+			logger.Error().Err(err).Interface("cnt", cnt).Msg("the record required customized vCard, which has error")
 			return err
 		}
+	} else if err := generateVCard(ctx, cnt, vcardVersion); err != nil { // vCard file is not exist
+		return err
 	}
 
-	return nil
+	return geneateQRCodeByFile(cnt.VcfFname, qrOpts)
+}
+
+// qrOptions controls how a QR code PNG is rendered: its size, error
+// correction level, colors and an optional center logo.
+type qrOptions struct {
+	Size       int
+	ECC        qrcode.RecoveryLevel
+	Foreground color.Color
+	Background color.Color
+	LogoPath   string
+}
+
+// buildQROptions reads the --qr-* flags into a qrOptions, upgrading the
+// error-correction level to H when a logo is requested, since overlaying a
+// logo needs the extra redundancy to stay scannable.
+func buildQROptions(c *cli.Context) (*qrOptions, error) {
+	ecc, err := parseQRECC(c.String("qr-ecc"))
+	if err != nil {
+		return nil, err
+	}
+
+	fg, err := parseHexColor(c.String("qr-fg"))
+	if err != nil {
+		return nil, err
+	}
+	bg, err := parseHexColor(c.String("qr-bg"))
+	if err != nil {
+		return nil, err
+	}
+
+	logoPath := c.String("qr-logo")
+	if logoPath != "" && ecc != qrcode.Highest {
+		if c.IsSet("qr-ecc") {
+			log.Ctx(c.Context).Warn().Msg("a QR logo was requested; overriding --qr-ecc to H so the code stays scannable")
+		}
+		ecc = qrcode.Highest
+	}
+
+	return &qrOptions{
+		Size:       c.Int("qr-size"),
+		ECC:        ecc,
+		Foreground: fg,
+		Background: bg,
+		LogoPath:   logoPath,
+	}, nil
+}
+
+// parseQRECC maps the L|M|Q|H error-correction levels from the QR spec onto
+// go-qrcode's RecoveryLevel.
+func parseQRECC(level string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	default:
+		return qrcode.Medium, fmt.Errorf("invalid QR error-correction level: %s", level)
+	}
 }
 
-func geneateQRCodeByFile(vcfFname string) error {
+// parseHexColor parses a "#RRGGBB" string into an opaque color.
+func parseHexColor(s string) (color.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return nil, fmt.Errorf("invalid color, expected #RRGGBB: %s", s)
+	}
+
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil, fmt.Errorf("invalid color, expected #RRGGBB: %s", s)
+	}
+
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}, nil
+}
+
+func geneateQRCodeByFile(vcfFname string, qrOpts *qrOptions) error {
 	var outFname string
 	if fname := strings.Trim(vcfFname, defaultVCFExtension); fname != "" {
 		outFname = fmt.Sprintf("%s.png", fname)
@@ -183,18 +403,109 @@ func geneateQRCodeByFile(vcfFname string) error {
 		return fmt.Errorf("invalid vCard file name: %s", vcfFname)
 	}
 
-	if content, err := os.ReadFile(vcfFname); err != nil {
+	content, err := os.ReadFile(vcfFname)
+	if err != nil {
 		return err
 	} else if len(content) == 0 {
 		return fmt.Errorf("empty vCard file: %s", vcfFname)
+	}
+
+	qr, err := qrcode.New(string(content), qrOpts.ECC)
+	if err != nil {
+		return err
+	}
+	qr.ForegroundColor = qrOpts.Foreground
+	qr.BackgroundColor = qrOpts.Background
+
+	img := qr.Image(qrOpts.Size)
+	if qrOpts.LogoPath != "" {
+		if img, err = overlayLogo(img, qrOpts.LogoPath); err != nil {
+			return err
+		}
+	}
+
+	return writePNG(outFname, img)
+}
+
+// overlayLogo draws logoPath, scaled down to 1/5 of qrImg's size, centered
+// on top of qrImg.
+func overlayLogo(qrImg image.Image, logoPath string) (image.Image, error) {
+	logoFile, err := os.Open(logoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer logoFile.Close()
+
+	logoImg, _, err := image.Decode(logoFile)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := qrImg.Bounds()
+	logoSize := bounds.Dx() / 5
+	resizedLogo := resizeNearest(logoImg, logoSize, logoSize)
+
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, qrImg, image.Point{}, draw.Src)
+
+	offsetX := bounds.Min.X + (bounds.Dx()-logoSize)/2
+	offsetY := bounds.Min.Y + (bounds.Dy()-logoSize)/2
+	logoRect := image.Rect(offsetX, offsetY, offsetX+logoSize, offsetY+logoSize)
+	draw.Draw(canvas, logoRect, resizedLogo, image.Point{}, draw.Over)
+
+	return canvas, nil
+}
+
+// resizeNearest scales src to width x height using nearest-neighbor
+// sampling.
+func resizeNearest(src image.Image, width, height int) image.Image {
+	srcBounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := srcBounds.Min.Y + y*srcBounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := srcBounds.Min.X + x*srcBounds.Dx()/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+func writePNG(outFname string, img image.Image) error {
+	fout, err := os.Create(outFname)
+	if err != nil {
+		return err
+	}
+	defer fout.Close()
+
+	return png.Encode(fout, img)
+}
+
+// listReader reads a contact list from a file under dataFolder and returns
+// the parsed contacts. Implementations differ by source file format.
+type listReader interface {
+	readContacts(ctx context.Context, dataFolder, lstFname string) ([]*contact, error)
+}
+
+// parseInputList dispatches to a listReader based on the extension of
+// lstFname: ".xlsx" is read directly via excelize, anything else is treated
+// as the legacy TAB delimited, UTF-16 LE with BOM export from Excel.
+func parseInputList(ctx context.Context, dataFolder, lstFname, sheetName string) ([]*contact, error) {
+	var reader listReader
+	if strings.EqualFold(filepath.Ext(lstFname), ".xlsx") {
+		reader = &xlsxReader{sheetName: sheetName}
 	} else {
-		qrcode.WriteFile(string(content), qrcode.Medium, 256, outFname)
+		reader = &tsvUTF16Reader{}
 	}
 
-	return nil
+	return reader.readContacts(ctx, dataFolder, lstFname)
 }
 
-func parseInputList(ctx context.Context, dataFolder, lstFname string) ([]*contact, error) {
+// tsvUTF16Reader reads the legacy TAB delimited, UTF-16 LE with BOM export
+// from Excel.
+type tsvUTF16Reader struct{}
+
+func (tsvUTF16Reader) readContacts(ctx context.Context, dataFolder, lstFname string) ([]*contact, error) {
 	logger := log.Ctx(ctx).With().Str("folder", dataFolder).Str("lst", lstFname).Logger()
 	fin, err := os.Open(filepath.Join(dataFolder, lstFname))
 	if err != nil {
@@ -208,6 +519,15 @@ func parseInputList(ctx context.Context, dataFolder, lstFname string) ([]*contac
 	r := csv.NewReader(transform.NewReader(fin, decoder))
 	r.Comma = '\t' // Set the delimiter to tab
 
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	headerIdx := buildHeaderIndex(header)
+	if err := validateHeaderIndex(headerIdx); err != nil {
+		return nil, err
+	}
+
 	contactLst := make([]*contact, 0)
 	for {
 		rec, err := r.Read()
@@ -218,32 +538,53 @@ func parseInputList(ctx context.Context, dataFolder, lstFname string) ([]*contac
 			return nil, err
 		}
 
-		if rec[0] == "no" {
-			continue // the first line
-		}
-
-		if v, err := strconv.ParseInt(rec[0], 10, 64); err != nil || v == 0 {
-			if err != nil {
-				logger.Error().Err(err).Msgf("error to parse the row: %s", rec)
-			}
+		oneContact, skip := rowToContact(logger, headerIdx, rec, dataFolder)
+		if skip {
 			continue
 		}
 
-		oneContact := new(contact)
-		oneContact.Class = rec[1]
-		oneContact.Fullname = rec[2]
-		oneContact.VcfFname = filepath.Join(dataFolder, rec[3]+defaultVCFExtension)
-		oneContact.Cell = formatCellNo(rec[4])
-		oneContact.Email = rec[5]
-		if v, err := strconv.ParseInt(rec[6], 10, 64); err != nil || v < 0 {
-			if err != nil {
-				logger.Error().Err(err).Msgf("error to parse the answer of the row: %s", rec)
-			} else {
-				logger.Debug().Msgf("skip the cancelled record: %s", rec)
-			}
+		contactLst = append(contactLst, oneContact)
+	}
+
+	return contactLst, nil
+}
+
+// xlsxReader reads the contact list directly from an Excel workbook, mapping
+// columns by header name so they may appear in any order.
+type xlsxReader struct {
+	sheetName string
+}
+
+func (r xlsxReader) readContacts(ctx context.Context, dataFolder, lstFname string) ([]*contact, error) {
+	sheetName := r.sheetName
+	if sheetName == "" {
+		sheetName = defaultListSheetName
+	}
+
+	logger := log.Ctx(ctx).With().Str("folder", dataFolder).Str("lst", lstFname).Str("sheet", sheetName).Logger()
+	fin, err := excelize.OpenFile(filepath.Join(dataFolder, lstFname))
+	if err != nil {
+		return nil, err
+	}
+	defer fin.Close()
+
+	rows, err := fin.GetRows(sheetName)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sheet %q of %s has no rows", sheetName, lstFname)
+	}
+	headerIdx := buildHeaderIndex(rows[0])
+	if err := validateHeaderIndex(headerIdx); err != nil {
+		return nil, err
+	}
+
+	contactLst := make([]*contact, 0, len(rows)-1)
+	for _, rec := range rows[1:] {
+		oneContact, skip := rowToContact(logger, headerIdx, rec, dataFolder)
+		if skip {
 			continue
-		} else {
-			oneContact.Answer = answerType(v)
 		}
 
 		contactLst = append(contactLst, oneContact)
@@ -252,6 +593,77 @@ func parseInputList(ctx context.Context, dataFolder, lstFname string) ([]*contac
 	return contactLst, nil
 }
 
+// requiredHeaders are the columns rowToContact cannot do without; a missing
+// one would otherwise silently read as "" for every row.
+var requiredHeaders = []string{"no", "class", "name", "vcf", "answer"}
+
+// buildHeaderIndex maps lower-cased, trimmed header names to their column
+// index so rows can be read by column name rather than fixed position.
+func buildHeaderIndex(header []string) map[string]int {
+	idx := make(map[string]int, len(header))
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	return idx
+}
+
+// validateHeaderIndex checks that every column in requiredHeaders was found
+// by buildHeaderIndex, returning a clear error naming the first one missing.
+func validateHeaderIndex(headerIdx map[string]int) error {
+	for _, h := range requiredHeaders {
+		if _, ok := headerIdx[h]; !ok {
+			return fmt.Errorf("missing required column: %s", h)
+		}
+	}
+	return nil
+}
+
+// rowToContact builds a contact from rec using headerIdx to locate the "no",
+// "class", "name", "vcf", "cell", "email" and "answer" columns, plus the
+// optional "org", "title", "address", "url", "birthday" and "photo" columns.
+// skip is true when the row should be dropped (missing/zero "no", or a
+// cancelled/invalid answer); an unparsable "no" or "answer" is logged.
+func rowToContact(logger zerolog.Logger, headerIdx map[string]int, rec []string, dataFolder string) (oneContact *contact, skip bool) {
+	field := func(name string) string {
+		if i, ok := headerIdx[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	if v, err := strconv.ParseInt(field("no"), 10, 64); err != nil || v == 0 {
+		if err != nil {
+			logger.Error().Err(err).Msgf("error to parse the row: %s", rec)
+		}
+		return nil, true
+	}
+
+	oneContact = new(contact)
+	oneContact.Class = field("class")
+	oneContact.Fullname = field("name")
+	oneContact.VcfFname = filepath.Join(dataFolder, field("vcf")+defaultVCFExtension)
+	oneContact.Cell = formatCellNo(field("cell"))
+	oneContact.Email = field("email")
+	oneContact.Org = field("org")
+	oneContact.Title = field("title")
+	oneContact.Address = field("address")
+	oneContact.URL = field("url")
+	oneContact.Birthday = field("birthday")
+	oneContact.Photo = field("photo")
+	if v, err := strconv.ParseInt(field("answer"), 10, 64); err != nil || v < 0 {
+		if err != nil {
+			logger.Error().Err(err).Msgf("error to parse the answer of the row: %s", rec)
+		} else {
+			logger.Debug().Msgf("skip the cancelled record: %s", rec)
+		}
+		return nil, true
+	} else {
+		oneContact.Answer = answerType(v)
+	}
+
+	return oneContact, false
+}
+
 func formatCellNo(orig string) string {
 	if len(orig) != 10 {
 		return orig
@@ -263,32 +675,84 @@ func formatCellNo(orig string) string {
 	return fmt.Sprintf("+886 %s-%s-%s", orig[1:4], orig[4:7], orig[7:])
 }
 
-func generateVCard(ctx context.Context, cnt *contact) error {
+// telURI strips everything but digits and a leading '+' from a formatted
+// phone number, since RFC 3966 tel: URIs don't allow the space formatCellNo
+// inserts between the country code and the rest of the number.
+func telURI(cell string) string {
+	var sb strings.Builder
+	for i, r := range cell {
+		if r == '+' && i == 0 {
+			sb.WriteRune(r)
+			continue
+		}
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func generateVCard(ctx context.Context, cnt *contact, version string) error {
 	logger := log.Ctx(ctx).With().Str("fn", cnt.Fullname).Logger()
 	var sb strings.Builder
 
 	// begin of vCard
-	sb.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	sb.WriteString(fmt.Sprintf("BEGIN:VCARD\nVERSION:%s\n", version))
 
 	// FN
-	sb.WriteString(fmt.Sprintf("FN:%s\n", cnt.Fullname))
+	sb.WriteString(foldLine(fmt.Sprintf("FN:%s", cnt.Fullname)))
 	// N
 	nRunes := []rune(cnt.Fullname)
-	sb.WriteString(fmt.Sprintf("N:%s;%s;;;\n", string(nRunes[0]), string(nRunes[1:])))
+	sb.WriteString(foldLine(fmt.Sprintf("N:%s;%s;;;", string(nRunes[0]), string(nRunes[1:]))))
 
+	// ORG/TITLE/ADR/URL/BDAY/PHOTO/EMAIL/TEL all carry personal information,
+	// so like EMAIL/TEL they are withheld unless the contact agreed to share
+	// (cnt.Answer == AnswerYes).
 	if cnt.Answer == AnswerYes {
+		if cnt.Org != "" {
+			sb.WriteString(foldLine(fmt.Sprintf("ORG:%s", cnt.Org)))
+		}
+		if cnt.Title != "" {
+			sb.WriteString(foldLine(fmt.Sprintf("TITLE:%s", cnt.Title)))
+		}
+		if cnt.Address != "" {
+			sb.WriteString(foldLine(fmt.Sprintf("ADR:;;%s;;;;", cnt.Address)))
+		}
+		if cnt.URL != "" {
+			sb.WriteString(foldLine(fmt.Sprintf("URL:%s", cnt.URL)))
+		}
+		if cnt.Birthday != "" {
+			sb.WriteString(foldLine(fmt.Sprintf("BDAY:%s", cnt.Birthday)))
+		}
+
 		// Email
 		if cnt.Email != "" {
-			sb.WriteString(fmt.Sprintf("EMAIL;TYPE=INTERNET;TYPE=WORK:%s\n", cnt.Email))
+			if version == vcardVersion4 {
+				sb.WriteString(foldLine(fmt.Sprintf("EMAIL;TYPE=work:%s", cnt.Email)))
+			} else {
+				sb.WriteString(foldLine(fmt.Sprintf("EMAIL;TYPE=INTERNET;TYPE=WORK:%s", cnt.Email)))
+			}
 		}
 		// TEL/CELL
 		if cnt.Cell != "" {
-			sb.WriteString(fmt.Sprintf("TEL;TYPE=CELL:%s\n", cnt.Cell))
+			if version == vcardVersion4 {
+				sb.WriteString(foldLine(fmt.Sprintf("TEL;TYPE=cell,voice;VALUE=uri:tel:%s", telURI(cnt.Cell))))
+			} else {
+				sb.WriteString(foldLine(fmt.Sprintf("TEL;TYPE=CELL:%s", cnt.Cell)))
+			}
+		}
+
+		if cnt.Photo != "" {
+			photoProp, err := buildPhotoProperty(cnt.Photo)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(foldLine(photoProp))
 		}
 	}
 
 	// NOTE
-	sb.WriteString(fmt.Sprintf("NOTE:建中42屆%s班同學\n", cnt.Class))
+	sb.WriteString(foldLine(fmt.Sprintf("NOTE:建中42屆%s班同學", cnt.Class)))
 
 	// end of vCard
 	sb.WriteString("END:VCARD\n")
@@ -301,8 +765,67 @@ func generateVCard(ctx context.Context, cnt *contact) error {
 	return nil
 }
 
-func generateExcelFile(ctx context.Context, outFname string, contactList []*contact) error {
-	logger := log.Ctx(ctx).With().Str("out", outFname).Logger()
+// buildPhotoProperty builds the vCard PHOTO property for photo, which is
+// either an http(s) URL (emitted as PHOTO;VALUE=URI:) or a local image path
+// (read and inlined as PHOTO;ENCODING=b;TYPE=JPEG:).
+func buildPhotoProperty(photo string) (string, error) {
+	if strings.HasPrefix(photo, "http://") || strings.HasPrefix(photo, "https://") {
+		return fmt.Sprintf("PHOTO;VALUE=URI:%s", photo), nil
+	}
+
+	data, err := os.ReadFile(photo)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("PHOTO;ENCODING=b;TYPE=JPEG:%s", base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// foldLine terminates a single vCard content line with "\n", folding it
+// across multiple physical lines per RFC 6350 section 3.2 when it is longer
+// than 75 octets: continuation lines are indented by one space and no
+// UTF-8 rune is split across a fold.
+func foldLine(line string) string {
+	const maxLineLen = 75
+
+	var sb strings.Builder
+	remaining := line
+	for first := true; ; first = false {
+		limit := maxLineLen
+		if !first {
+			limit = maxLineLen - 1 // leave room for the continuation space
+		}
+
+		if len(remaining) <= limit {
+			if !first {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(remaining)
+			sb.WriteString("\n")
+			return sb.String()
+		}
+
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(remaining[cut]) {
+			cut--
+		}
+
+		if !first {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(remaining[:cut])
+		sb.WriteString("\n")
+		remaining = remaining[cut:]
+	}
+}
+
+// streamAutoThreshold is the contact count above which generateExcelFile
+// switches to the StreamWriter based implementation even without --stream,
+// since building thousands of QR images in memory is too slow otherwise.
+const streamAutoThreshold = 500
+
+func generateExcelFile(ctx context.Context, outFname string, contactList []*contact, useStream bool) error {
+	logger := log.Ctx(ctx).With().Str("out", outFname).Bool("stream", useStream).Logger()
 	fout := excelize.NewFile()
 	defer func() {
 		if err := fout.Close(); err != nil {
@@ -310,10 +833,23 @@ func generateExcelFile(ctx context.Context, outFname string, contactList []*cont
 		}
 	}()
 
-	if err := fillListSheet(ctx, fout, contactList); err != nil {
-		return err
+	if useStream {
+		if err := fillListSheetStream(ctx, fout, contactList); err != nil {
+			return err
+		}
+		if err := genQRCodeSheetStream(ctx, fout, contactList); err != nil {
+			return err
+		}
+	} else {
+		if err := fillListSheet(ctx, fout, contactList); err != nil {
+			return err
+		}
+		if err := genQRCodeSheet(ctx, fout, contactList); err != nil {
+			return err
+		}
 	}
-	if err := genQRCodeSheet(ctx, fout, contactList); err != nil {
+
+	if err := genSummarySheet(ctx, fout, contactList); err != nil {
 		return err
 	}
 
@@ -326,6 +862,37 @@ func generateExcelFile(ctx context.Context, outFname string, contactList []*cont
 	return nil
 }
 
+// fillListSheetStream is the StreamWriter based equivalent of fillListSheet,
+// used for large contact lists where building the sheet in memory is too
+// slow.
+func fillListSheetStream(ctx context.Context, fout *excelize.File, contactList []*contact) error {
+	const sheet1Name = "Sheet1"
+
+	logger := log.Ctx(ctx)
+
+	sw, err := fout.NewStreamWriter(sheet1Name)
+	if err != nil {
+		return err
+	}
+
+	if err := sw.SetRow("A1", []interface{}{"class", "name", "answer"}); err != nil {
+		return err
+	}
+
+	for idx, cnt := range contactList {
+		cellRef, err := excelize.CoordinatesToCellName(1, idx+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cellRef, []interface{}{cnt.Class, cnt.Fullname, int(cnt.Answer)}); err != nil {
+			logger.Error().Err(err).Msg("error to set contact row")
+			break
+		}
+	}
+
+	return sw.Flush()
+}
+
 func fillListSheet(ctx context.Context, fout *excelize.File, contactList []*contact) error {
 	const sheet1Name = "Sheet1"
 
@@ -338,6 +905,9 @@ func fillListSheet(ctx context.Context, fout *excelize.File, contactList []*cont
 	if err := fout.SetCellValue(sheet1Name, "B1", "name"); err != nil {
 		return err
 	}
+	if err := fout.SetCellValue(sheet1Name, "C1", "answer"); err != nil {
+		return err
+	}
 
 	for idx, cnt := range contactList {
 		rowID := strconv.FormatInt(int64(idx+2), 10)
@@ -349,6 +919,10 @@ func fillListSheet(ctx context.Context, fout *excelize.File, contactList []*cont
 			logger.Error().Err(err).Msg("error to set contact name")
 			break
 		}
+		if err := fout.SetCellValue(sheet1Name, "C"+rowID, int(cnt.Answer)); err != nil {
+			logger.Error().Err(err).Msg("error to set contact answer")
+			break
+		}
 	}
 
 	return nil
@@ -448,3 +1022,171 @@ func genQRCodeSheet(ctx context.Context, fout *excelize.File, contactList []*con
 	}
 	return nil
 }
+
+// genQRCodeSheetStream is the StreamWriter based equivalent of
+// genQRCodeSheet. excelize only supports mixing StreamWriter cell values
+// with AddPicture once the stream has been flushed, so the text cells are
+// written first and the QR images are added afterwards; styling and page
+// layout are skipped since the StreamWriter API does not support them.
+func genQRCodeSheetStream(ctx context.Context, fout *excelize.File, contactList []*contact) error {
+	const sheet2Name = "Sheet2"
+
+	logger := log.Ctx(ctx)
+	// Create a new sheet.
+	if _, err := fout.NewSheet(sheet2Name); err != nil {
+		return err
+	}
+
+	sw, err := fout.NewStreamWriter(sheet2Name)
+	if err != nil {
+		return err
+	}
+
+	// Two contacts share a physical row (column A and column B), so both
+	// must be written together in a single SetRow call: StreamWriter
+	// rejects writing to a row it has already seen.
+	for pairStart := 0; pairStart < len(contactList); pairStart += 2 {
+		rowID := pairStart + 1
+		rowValues := []interface{}{contactList[pairStart].Class + " " + contactList[pairStart].Fullname}
+		if pairStart+1 < len(contactList) {
+			cnt := contactList[pairStart+1]
+			rowValues = append(rowValues, cnt.Class+" "+cnt.Fullname)
+		}
+
+		cellRef, err := excelize.CoordinatesToCellName(1, rowID)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cellRef, rowValues); err != nil {
+			logger.Error().Err(err).Msg("error to set QR title")
+			break
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	enabled := true
+	defGraphicOpts := &excelize.GraphicOptions{
+		PrintObject:     &enabled,
+		LockAspectRatio: true,
+		OffsetX:         16,
+		OffsetY:         1,
+		ScaleX:          0.78,
+		ScaleY:          0.711,
+		Positioning:     "oneCell",
+	}
+	for idx, cnt := range contactList {
+		rowID := int(idx/2)*2 + 1
+		colID := "A"
+		if (idx % 2) != 0 {
+			colID = "B"
+		}
+		imgCellID := colID + strconv.FormatInt(int64(rowID+1), 10)
+		imgFname := strings.ReplaceAll(cnt.VcfFname, defaultVCFExtension, ".png")
+
+		if err := fout.AddPicture(sheet2Name, imgCellID, imgFname, defGraphicOpts); err != nil {
+			logger.Error().Err(err).Msg("error to add QR code")
+			break
+		}
+	}
+	return nil
+}
+
+// genSummarySheet adds a sheet that aggregates contactList by Class: a
+// table of class, count, answered-yes, answered-custom and answered-no,
+// plus a bar chart of contact counts per class. The counts are live
+// COUNTIF/COUNTIFS formulas against Sheet1, so editing the list in Excel
+// keeps the summary up to date.
+func genSummarySheet(ctx context.Context, fout *excelize.File, contactList []*contact) error {
+	const summarySheetName = "Summary"
+
+	logger := log.Ctx(ctx)
+	if _, err := fout.NewSheet(summarySheetName); err != nil {
+		return err
+	}
+
+	headers := []string{"class", "count", "answered-yes", "answered-custom", "answered-no"}
+	for col, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+		if err := fout.SetCellValue(summarySheetName, cell, h); err != nil {
+			return err
+		}
+	}
+
+	classes := orderedClasses(contactList)
+	if len(classes) == 0 {
+		return nil
+	}
+
+	classRange := fmt.Sprintf("Sheet1!$A$2:$A$%d", len(contactList)+1)
+	answerRange := fmt.Sprintf("Sheet1!$C$2:$C$%d", len(contactList)+1)
+
+	for idx, className := range classes {
+		row := idx + 2
+		classCell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := fout.SetCellValue(summarySheetName, classCell, className); err != nil {
+			return err
+		}
+
+		countCell, _ := excelize.CoordinatesToCellName(2, row)
+		if err := fout.SetCellFormula(summarySheetName, countCell, fmt.Sprintf("=COUNTIF(%s,%s)", classRange, classCell)); err != nil {
+			logger.Error().Err(err).Msg("error to set class count formula")
+			return err
+		}
+
+		yesCell, _ := excelize.CoordinatesToCellName(3, row)
+		if err := fout.SetCellFormula(summarySheetName, yesCell, fmt.Sprintf("=COUNTIFS(%s,%s,%s,1)", classRange, classCell, answerRange)); err != nil {
+			logger.Error().Err(err).Msg("error to set answered-yes formula")
+			return err
+		}
+
+		customCell, _ := excelize.CoordinatesToCellName(4, row)
+		if err := fout.SetCellFormula(summarySheetName, customCell, fmt.Sprintf("=COUNTIFS(%s,%s,%s,2)", classRange, classCell, answerRange)); err != nil {
+			logger.Error().Err(err).Msg("error to set answered-custom formula")
+			return err
+		}
+
+		noCell, _ := excelize.CoordinatesToCellName(5, row)
+		if err := fout.SetCellFormula(summarySheetName, noCell, fmt.Sprintf("=COUNTIFS(%s,%s,%s,0)", classRange, classCell, answerRange)); err != nil {
+			logger.Error().Err(err).Msg("error to set answered-no formula")
+			return err
+		}
+	}
+
+	lastRow := len(classes) + 1
+	if err := fout.AddChart(summarySheetName, "G2", &excelize.Chart{
+		Type: excelize.Bar,
+		Series: []excelize.ChartSeries{
+			{
+				Name:       fmt.Sprintf("%s!$B$1", summarySheetName),
+				Categories: fmt.Sprintf("%s!$A$2:$A$%d", summarySheetName, lastRow),
+				Values:     fmt.Sprintf("%s!$B$2:$B$%d", summarySheetName, lastRow),
+			},
+		},
+		Title: excelize.ChartTitle{
+			Paragraph: []excelize.RichTextRun{
+				{Text: "Contacts by Class"},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// orderedClasses returns the distinct, non-empty Class values in
+// contactList, in order of first appearance.
+func orderedClasses(contactList []*contact) []string {
+	seen := make(map[string]bool)
+	classes := make([]string, 0)
+	for _, cnt := range contactList {
+		if cnt.Class == "" || seen[cnt.Class] {
+			continue
+		}
+		seen[cnt.Class] = true
+		classes = append(classes, cnt.Class)
+	}
+	return classes
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestGenerateExcelFileStream(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	contactList := make([]*contact, 0, 3)
+	for i := 1; i <= 3; i++ {
+		vcfFname := filepath.Join(tmpDir, fmt.Sprintf("c%d.vcf", i))
+		if err := writeTestPNG(filepath.Join(tmpDir, fmt.Sprintf("c%d.png", i))); err != nil {
+			t.Fatalf("writeTestPNG: %v", err)
+		}
+		contactList = append(contactList, &contact{
+			Class:    "101",
+			Fullname: fmt.Sprintf("Student %d", i),
+			VcfFname: vcfFname,
+			Answer:   AnswerYes,
+		})
+	}
+
+	outFname := filepath.Join(tmpDir, "out.xlsx")
+	if err := generateExcelFile(context.Background(), outFname, contactList, true); err != nil {
+		t.Fatalf("generateExcelFile: %v", err)
+	}
+
+	fout, err := excelize.OpenFile(outFname)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer fout.Close()
+
+	wantCells := map[string]string{
+		"A1": "101 Student 1",
+		"B1": "101 Student 2",
+		"A3": "101 Student 3",
+	}
+	for cell, want := range wantCells {
+		got, err := fout.GetCellValue("Sheet2", cell)
+		if err != nil {
+			t.Fatalf("GetCellValue(Sheet2!%s): %v", cell, err)
+		}
+		if got != want {
+			t.Errorf("Sheet2!%s = %q, want %q", cell, got, want)
+		}
+	}
+
+	if idx, err := fout.GetSheetIndex("Summary"); err != nil || idx == -1 {
+		t.Fatalf("Summary sheet missing: idx=%d, err=%v", idx, err)
+	}
+	if got, err := fout.GetCellValue("Summary", "A2"); err != nil || got != "101" {
+		t.Errorf("Summary!A2 = %q, err=%v, want %q", got, err, "101")
+	}
+}
+
+func TestValidateHeaderIndex(t *testing.T) {
+	idx := buildHeaderIndex([]string{"No", "Class", "fullname", "VCF", "Answer"})
+	if err := validateHeaderIndex(idx); err == nil {
+		t.Fatal("validateHeaderIndex: want error for misspelled \"name\" header, got nil")
+	}
+
+	idx = buildHeaderIndex([]string{"No", "Class", "Name", "VCF", "Answer"})
+	if err := validateHeaderIndex(idx); err != nil {
+		t.Errorf("validateHeaderIndex: unexpected error for complete header: %v", err)
+	}
+}
+
+func writeTestPNG(path string) error {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.White)
+
+	fout, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fout.Close()
+
+	return png.Encode(fout, img)
+}